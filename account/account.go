@@ -1,6 +1,7 @@
 package account
 
 import (
+	"context"
 	"crypto/sha256"
 	"fmt"
 	"github.com/iotaledger/iota.go/account/deposit"
@@ -29,6 +30,9 @@ type Account interface {
 	Shutdown() error
 	// Send sends the specified amounts to the given recipients.
 	Send(recipients ...Recipient) (bundle.Bundle, error)
+	// SendContext behaves like Send but additionally bounds input selection by ctx.
+	// Bundle construction, PoW and broadcast run to completion regardless of ctx.
+	SendContext(ctx context.Context, recipients ...Recipient) (bundle.Bundle, error)
 	// AllocateDepositRequest generates a new deposit request.
 	AllocateDepositRequest(req *deposit.Request) (*deposit.Conditions, error)
 	// AvailableBalance gets the current available balance.
@@ -44,6 +48,26 @@ type Account interface {
 	IsNew() (bool, error)
 	// UpdateSettings updates the settings of the account in a safe and synchronized manner.
 	UpdateSettings(setts *Settings) error
+	// Reserve earmarks the given amount of funds for the given duration, preventing them
+	// from being selected as inputs by concurrent Send/AvailableBalance calls.
+	// The reservation is automatically released once the timeout elapses.
+	Reserve(amount uint64, timeout time.Duration) (ReservationID, error)
+	// Release frees up the funds earmarked by the given reservation before its timeout elapses.
+	Release(id ReservationID) error
+	// SendWithReservation sends the specified amounts to the given recipients, drawing
+	// exclusively from the inputs earmarked by the given reservation.
+	SendWithReservation(id ReservationID, recipients ...Recipient) (bundle.Bundle, error)
+	// WatchAddress starts tracking an externally-owned or previously generated address
+	// whose key index is not derived from this account's seed.
+	WatchAddress(addr Hash, opts WatchOptions) error
+	// UnwatchAddress stops tracking the given watched address.
+	UnwatchAddress(addr Hash) error
+	// MonitoredAddresses returns every address this account should be watched
+	// for incoming transfers on: its allocated deposit addresses plus any
+	// addresses added via WatchAddress. It lets incoming-transfer poller plugins
+	// registered via WithPlugin poll watched addresses as well as deposit
+	// addresses; the account itself does not call it.
+	MonitoredAddresses() (Hashes, error)
 }
 
 // Recipient is a bundle.Transfer but with a nicer name.
@@ -77,8 +101,9 @@ func newAccount(setts *Settings) (Account, error) {
 		return nil, err
 	}
 	return &account{
-		id:    fmt.Sprintf("%x", sha256.Sum256([]byte(seed))),
-		setts: setts,
+		id:           fmt.Sprintf("%x", sha256.Sum256([]byte(seed))),
+		setts:        setts,
+		reservations: map[ReservationID]*reservation{},
 	}, nil
 }
 
@@ -95,6 +120,12 @@ type account struct {
 
 	// addr
 	lastKeyIndex uint64
+
+	// reservations
+	resMu            sync.Mutex
+	reservations     map[ReservationID]*reservation
+	reservationSeq   uint64
+	reservationsQuit chan struct{}
 }
 
 func (acc *account) ID() string {
@@ -102,6 +133,13 @@ func (acc *account) ID() string {
 }
 
 func (acc *account) Send(recipients ...Recipient) (bundle.Bundle, error) {
+	return acc.SendContext(context.Background(), recipients...)
+}
+
+// SendContext behaves like Send but returns ctx.Err() if ctx is cancelled or
+// its deadline is exceeded during input selection. Bundle construction, PoW
+// and broadcast do not observe ctx.
+func (acc *account) SendContext(ctx context.Context, recipients ...Recipient) (bundle.Bundle, error) {
 	acc.mu.Lock()
 	defer acc.mu.Unlock()
 
@@ -118,7 +156,7 @@ func (acc *account) Send(recipients ...Recipient) (bundle.Bundle, error) {
 		}
 	}
 
-	return acc.send(recipients)
+	return acc.send(ctx, recipients)
 }
 
 func (acc *account) AllocateDepositRequest(req *deposit.Request) (*deposit.Conditions, error) {
@@ -202,6 +240,12 @@ func (acc *account) UpdateSettings(setts *Settings) error {
 func (acc *account) Start() error {
 	acc.mu.Lock()
 	defer acc.mu.Unlock()
+
+	// bring the store's on-disk schema up to date before touching it any further
+	if err := store.Migrate(acc.setts.store); err != nil {
+		return errors.Wrap(err, "unable to migrate store in startup")
+	}
+
 	// ensure account is known to the store
 	state, err := acc.setts.store.LoadAccount(acc.id)
 	if err != nil {
@@ -214,6 +258,9 @@ func (acc *account) Start() error {
 		return err
 	}
 
+	acc.reservationsQuit = make(chan struct{})
+	go acc.reservationExpiryLoop(acc.reservationsQuit)
+
 	acc.running = true
 	return nil
 }
@@ -226,6 +273,7 @@ func (acc *account) Shutdown() error {
 	}
 
 	acc.running = false
+	close(acc.reservationsQuit)
 	if err := acc.shutdownPlugins(); err != nil {
 		return errors.Wrapf(err, "unable to shutdown plugin in shutdown op.")
 	}
@@ -253,16 +301,17 @@ func (acc *account) shutdownPlugins() error {
 }
 
 func (acc *account) allocateDepositRequest(req *deposit.Request) (*deposit.Conditions, error) {
-	seed, err := acc.setts.seedProv.Seed()
+	acc.lastKeyIndex++
+	addr, err := acc.setts.signer.DeriveAddress(acc.lastKeyIndex, acc.setts.securityLevel)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "unable to derive address in address gen. function")
 	}
-
-	acc.lastKeyIndex++
-	addr, err := address.GenerateAddress(seed, acc.lastKeyIndex, acc.setts.securityLevel, true)
+	checksum, err := address.Checksum(addr)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to generate address in address gen. function")
+		return nil, errors.Wrap(err, "unable to compute checksum in address gen. function")
 	}
+	addr += checksum
+
 	if err := acc.setts.store.WriteIndex(acc.id, acc.lastKeyIndex); err != nil {
 		return nil, errors.Wrapf(err, "unable to store next index (%d) in the store", acc.lastKeyIndex)
 	}
@@ -275,16 +324,18 @@ func (acc *account) allocateDepositRequest(req *deposit.Request) (*deposit.Condi
 	return &deposit.Conditions{Address: addr, Request: *req}, nil
 }
 
-func (acc *account) send(targets Recipients) (bundle.Bundle, error) {
+func (acc *account) send(ctx context.Context, targets Recipients) (bundle.Bundle, error) {
 	var inputs []api.Input
 	var remainderAddress *Hash
-	var err error
 	transferSum := targets.Sum()
 	forRemoval := []uint64{}
 
 	if transferSum > 0 {
 		// gather the total sum, inputs, addresses to remove from the store
-		sum, ins, rem, err := acc.setts.inputSelectionStrategy(acc, transferSum, false)
+		sum, ins, rem, err := acc.setts.inputSelectionStrategy(ctx, acc, transferSum, false)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to perform input selection in send op.")
 		}
@@ -303,6 +354,13 @@ func (acc *account) send(targets Recipients) (bundle.Bundle, error) {
 		}
 	}
 
+	return acc.sendWithInputs(targets, inputs, remainderAddress, forRemoval)
+}
+
+// sendWithInputs constructs, signs and broadcasts a bundle transferring to targets
+// using the given inputs, remainder address and the key indices to free from the store
+// once the transfer has been persisted.
+func (acc *account) sendWithInputs(targets Recipients, inputs []api.Input, remainderAddress *Hash, forRemoval []uint64) (bundle.Bundle, error) {
 	transfers := targets.AsTransfers()
 	currentTime, err := acc.setts.clock.Now()
 	if err != nil {
@@ -316,12 +374,7 @@ func (acc *account) send(targets Recipients) (bundle.Bundle, error) {
 		Timestamp:        &ts,
 	}
 
-	seed, err := acc.setts.seedProv.Seed()
-	if err != nil {
-		return nil, errors.Wrap(err, "unable to get seed from seed provider in send op.")
-	}
-
-	bundleTrytes, err := acc.setts.api.PrepareTransfers(seed, transfers, opts)
+	bundleTrytes, err := acc.prepareAndSign(transfers, opts, inputs)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to prepare transfers in send op.")
 	}
@@ -361,11 +414,25 @@ func (acc *account) send(targets Recipients) (bundle.Bundle, error) {
 }
 
 func (acc *account) availableBalance() (uint64, error) {
-	balance, _, _, err := acc.setts.inputSelectionStrategy(acc, 0, true)
-	return balance, err
+	balance, _, _, err := acc.setts.inputSelectionStrategy(context.Background(), acc, 0, true)
+	if err != nil {
+		return 0, err
+	}
+
+	watchedBalance, err := acc.watchedAddressesBalance()
+	if err != nil {
+		return 0, err
+	}
+
+	return balance + watchedBalance, nil
 }
 
 func (acc *account) totalBalance() (uint64, error) {
+	watchedBalance, err := acc.watchedAddressesBalance()
+	if err != nil {
+		return 0, err
+	}
+
 	state, err := acc.setts.store.LoadAccount(acc.id)
 	if err != nil {
 		return 0, errors.Wrap(err, "unable to load account state for querying total balance")
@@ -373,7 +440,7 @@ func (acc *account) totalBalance() (uint64, error) {
 
 	depositReqsCount := len(state.DepositRequests)
 	if depositReqsCount == 0 {
-		return 0, nil
+		return watchedBalance, nil
 	}
 
 	solidSubtangleMilestone, err := acc.setts.api.GetLatestSolidSubtangleMilestone()
@@ -382,20 +449,15 @@ func (acc *account) totalBalance() (uint64, error) {
 	}
 	subtangleHash := solidSubtangleMilestone.LatestSolidSubtangleMilestone
 
-	seed, err := acc.setts.seedProv.Seed()
-	if err != nil {
-		return 0, errors.Wrap(err, "unable to get seed from seed provider for computing total balance")
-	}
-
 	addrs := make(Hashes, len(state.DepositRequests))
 	var i int
 	for keyIndex, req := range state.DepositRequests {
-		addr, _ := address.GenerateAddress(seed, keyIndex, req.SecurityLevel, false)
+		addr, _ := acc.setts.signer.DeriveAddress(keyIndex, req.SecurityLevel)
 		addrs[i] = addr
 		i++
 	}
 
-	balances, err := acc.setts.api.GetBalances(addrs, 100, subtangleHash)
+	balances, err := queryBalancesParallel(context.Background(), acc, addrs, subtangleHash)
 	if err != nil {
 		return 0, errors.Wrap(err, "unable to fetch balances for computing total balance")
 	}
@@ -404,242 +466,5 @@ func (acc *account) totalBalance() (uint64, error) {
 		sum += balance
 	}
 
-	return sum, nil
-}
-
-// selects fulfilled and timed out deposit addresses as inputs.
-func defaultInputSelection(acc *account, transferValue uint64, balanceCheck bool) (uint64, []api.Input, []uint64, error) {
-	depositRequests, err := acc.setts.store.GetDepositRequests(acc.id)
-	if err != nil {
-		return 0, nil, nil, errors.Wrap(err, "unable to load account state for input selection")
-	}
-
-	// no deposit requests, therefore 0 balance
-	if len(depositRequests) == 0 {
-		if balanceCheck {
-			return 0, nil, nil, nil
-		}
-		// we can't fulfill any transfer value if we have no deposit requests
-		return 0, nil, nil, consts.ErrInsufficientBalance
-	}
-
-	// get the current solid subtangle milestone for doing each getBalance query with the same milestone
-	solidSubtangleMilestone, err := acc.setts.api.GetLatestSolidSubtangleMilestone()
-	if err != nil {
-		return 0, nil, nil, errors.Wrap(err, "unable to fetch latest solid subtangle milestone for input selection")
-	}
-	subtangleHash := solidSubtangleMilestone.LatestSolidSubtangleMilestone
-
-	// get current time to check for timed out addresses
-	now, err := acc.setts.clock.Now()
-	if err != nil {
-		return 0, nil, nil, errors.Wrap(err, "unable to get time for doing input selection")
-	}
-
-	type selection struct {
-		keyIndex uint64
-		req      *store.StoredDepositRequest
-	}
-
-	// primary addresses to use to try to use to fulfill the transfer value
-	primaryAddrs := Hashes{}
-	primarySelection := []selection{}
-
-	// secondary addresses which are only used to fulfill the transfer
-	// if the primary addresses couldn't fund the transfer.
-	// the reason for this is that timed out addresses must be checked
-	// for incoming consistent transfers, which is a slow operation.
-	secondaryAddrs := Hashes{}
-	secondarySelection := []selection{}
-
-	// addresses/indices to remove from the store
-	toRemove := []uint64{}
-
-	markForRemoval := func(keyIndex uint64) {
-		if balanceCheck {
-			return
-		}
-		toRemove = append(toRemove, keyIndex)
-	}
-
-	seed, err := acc.setts.seedProv.Seed()
-	if err != nil {
-		return 0, nil, nil, errors.Wrap(err, "unable to get seed from seed provider for doing input selection")
-	}
-
-	// iterate over all allocated deposit addresses
-	for keyIndex, req := range depositRequests {
-		// remainder address
-		if req.TimeoutAt == nil {
-			if req.ExpectedAmount == nil {
-				panic("remainder address in system without 'expected amount'")
-			}
-			addr, _ := address.GenerateAddress(seed, keyIndex, req.SecurityLevel, false)
-			primaryAddrs = append(primaryAddrs, addr)
-			primarySelection = append(primarySelection, selection{keyIndex, req})
-			continue
-		}
-
-		// timed out
-		if now.After(*req.TimeoutAt) {
-			addr, _ := address.GenerateAddress(seed, keyIndex, req.SecurityLevel, false)
-			secondaryAddrs = append(secondaryAddrs, addr)
-			secondarySelection = append(secondarySelection, selection{keyIndex, req})
-			continue
-		}
-
-		// multi
-		if req.MultiUse {
-			// multi use deposit addresses are only used
-			// when they are timed out, if they don't define an expected amount
-			if req.ExpectedAmount == nil {
-				continue
-			}
-			addr, _ := address.GenerateAddress(seed, keyIndex, req.SecurityLevel, false)
-			primaryAddrs = append(primaryAddrs, addr)
-			primarySelection = append(primarySelection, selection{keyIndex, req})
-			continue
-		}
-
-		// single
-		addr, _ := address.GenerateAddress(seed, keyIndex, req.SecurityLevel, false)
-		primaryAddrs = append(primaryAddrs, addr)
-		primarySelection = append(primarySelection, selection{keyIndex, req})
-	}
-
-	// get the balance of all addresses (also secondary) in one go
-	toQuery := append(primaryAddrs, secondaryAddrs...)
-	balances, err := acc.setts.api.GetBalances(toQuery, 100, subtangleHash)
-	if err != nil {
-		return 0, nil, nil, errors.Wrap(err, "unable to fetch balances of primary selected addresses for input selection")
-	}
-
-	inputs := []api.Input{}
-	addAsInput := func(input *api.Input) {
-		if balanceCheck {
-			return
-		}
-		inputs = append(inputs, *input)
-	}
-
-	// add addresses as inputs which fulfill their criteria
-	var sum uint64
-	for i := range primarySelection {
-		s := &primarySelection[i]
-		// skip addresses which have an expected amount which isn't reached however
-		if s.req.ExpectedAmount != nil && balances.Balances[i] < *s.req.ExpectedAmount {
-			continue
-		}
-		sum += balances.Balances[i]
-
-		// add the address as an input
-		if balances.Balances[i] <= 0 {
-			continue
-		}
-		addAsInput(&api.Input{
-			Address:  primaryAddrs[i],
-			KeyIndex: s.keyIndex,
-			Balance:  balances.Balances[i],
-			Security: s.req.SecurityLevel,
-		})
-
-		// mark the address for removal as it should be freed from the store
-		markForRemoval(s.keyIndex)
-		if sum > transferValue && !balanceCheck {
-			break
-		}
-	}
-
-	// if we didn't fulfill the transfer value,
-	// lets use the timed out addresses too to try to fulfill the transfer
-	if sum < transferValue || balanceCheck {
-		startPosSecondary := len(primarySelection)
-
-		for i := range secondarySelection {
-			secSelect := &secondarySelection[i]
-			addr := secondaryAddrs[i]
-
-			balance := balances.Balances[startPosSecondary+i]
-
-			// remove if there's no incoming consistent transfer
-			// and the balance is zero in order free up the store
-			if balance == 0 {
-				// check whether the timed out address has an incoming consistent value transfer,
-				// and if so, don't remove it from the store
-				if has, err := acc.hasIncomingConsistentValueTransfer(addr); has || err != nil {
-					continue
-				}
-				markForRemoval(secSelect.keyIndex)
-				continue
-			}
-			markForRemoval(secSelect.keyIndex)
-			sum += balance
-			addAsInput(&api.Input{
-				KeyIndex: secSelect.keyIndex,
-				Address:  addr,
-				Security: secSelect.req.SecurityLevel,
-				Balance:  balance,
-			})
-			if sum > transferValue && !balanceCheck {
-				break
-			}
-		}
-	}
-
-	if balanceCheck {
-		return sum, nil, nil, nil
-	}
-
-	if sum < transferValue {
-		return 0, nil, nil, consts.ErrInsufficientBalance
-	}
-	return sum, inputs, toRemove, nil
+	return sum + watchedBalance, nil
 }
-
-func (acc *account) hasIncomingConsistentValueTransfer(addr Hash) (bool, error) {
-	var has bool
-	bndls, err := acc.setts.api.GetBundlesFromAddresses(Hashes{addr}, true)
-	if err != nil {
-		return false, err
-	}
-	persisted := map[string]struct{}{}
-	for i := range bndls {
-		if *(bndls[i][0]).Persistence {
-			persisted[bndls[i][0].Bundle] = struct{}{}
-			continue
-		}
-
-		// skip reattachments of an already persisted bundle
-		if _, has := persisted[bndls[i][0].Bundle]; has {
-			continue
-		}
-
-		// check whether it's even a deposit to the address we are checking
-		var isDepositToAddr bool
-		for j := range bndls[i] {
-			if bndls[i][j].Value > 0 && bndls[i][j].Address == addr {
-				isDepositToAddr = true
-				break
-			}
-		}
-
-		// ignore this transfer as it isn't an incoming value transfer
-		if !isDepositToAddr {
-			continue
-		}
-
-		// here we have a bundle which is not yet confirmed
-		// and is depositing something onto this address.
-		// lets check it for its consistency
-		hash := bndls[i][0].Hash
-		consistent, _, err := acc.setts.api.CheckConsistency(hash)
-		if err != nil {
-			return false, errors.Wrapf(err, "unable to check consistency of tx %s in incoming consistent transfer check", hash)
-		}
-		if consistent {
-			has = true
-			break
-		}
-	}
-	return has, nil
-}
\ No newline at end of file
@@ -0,0 +1,26 @@
+// Package event defines the events an Account emits through its configured
+// EventMachine, so that a wallet frontend or logging layer can observe
+// account activity without polling its state.
+package event
+
+// Event identifies the kind of occurrence passed to an EventMachine's Emit call.
+type Event byte
+
+const (
+	// EventShutdown is emitted once an account has fully shut down.
+	EventShutdown Event = iota
+	// EventSendingTransfer is emitted right before a prepared bundle is broadcast.
+	EventSendingTransfer
+	// EventReservationExpired is emitted when a Reserve()'d set of inputs is
+	// released back into the pool after its timeout elapses without a
+	// matching SendWithReservation call.
+	EventReservationExpired
+)
+
+// EventMachine dispatches account events to whatever subscribers a caller
+// has registered; how subscription works is left to the concrete
+// implementation a Settings is configured with.
+type EventMachine interface {
+	// Emit dispatches event with its associated data to all subscribers.
+	Emit(data interface{}, event Event)
+}
@@ -0,0 +1,445 @@
+package account
+
+import (
+	"context"
+	"sync"
+
+	"github.com/iotaledger/iota.go/account/store"
+	"github.com/iotaledger/iota.go/address"
+	"github.com/iotaledger/iota.go/api"
+	"github.com/iotaledger/iota.go/consts"
+	. "github.com/iotaledger/iota.go/trinary"
+	"github.com/pkg/errors"
+)
+
+// defaultBalanceQueryBatchSize caps how many addresses are sent to the node
+// in a single GetBalances call, so that a batch of addresses behind the
+// selection concurrency limit queries the node in several round-trips
+// rather than one that a single slow/failing node could stall indefinitely.
+const defaultBalanceQueryBatchSize = 50
+
+// concurrency returns the configured selection concurrency, defaulting to 1
+// if unset, so the parallel helpers below degrade gracefully to serial work.
+func (setts *Settings) concurrency() int {
+	if setts.selectionConcurrency < 1 {
+		return 1
+	}
+	return setts.selectionConcurrency
+}
+
+// selects fulfilled and timed out deposit addresses as inputs.
+// Watched addresses are never considered, since the account holds no seed-derived
+// private key for them and therefore can't sign for them without an ExternalSigner.
+//
+// Balance and consistency queries are fanned out across a bounded worker pool
+// (Settings.SelectionConcurrency) instead of running serially, so that accounts
+// with hundreds of timed-out deposit addresses don't turn Send/AvailableBalance
+// into a multi-minute, purely sequential round-trip chain.
+func defaultInputSelection(ctx context.Context, acc *account, transferValue uint64, balanceCheck bool) (uint64, []api.Input, []uint64, error) {
+	depositRequests, err := acc.setts.store.GetDepositRequests(acc.id)
+	if err != nil {
+		return 0, nil, nil, errors.Wrap(err, "unable to load account state for input selection")
+	}
+
+	// no deposit requests, therefore 0 balance
+	if len(depositRequests) == 0 {
+		if balanceCheck {
+			return 0, nil, nil, nil
+		}
+		// we can't fulfill any transfer value if we have no deposit requests
+		return 0, nil, nil, consts.ErrInsufficientBalance
+	}
+
+	// get the current solid subtangle milestone for doing each getBalance query with the same milestone
+	solidSubtangleMilestone, err := acc.setts.api.GetLatestSolidSubtangleMilestone()
+	if err != nil {
+		return 0, nil, nil, errors.Wrap(err, "unable to fetch latest solid subtangle milestone for input selection")
+	}
+	subtangleHash := solidSubtangleMilestone.LatestSolidSubtangleMilestone
+
+	// get current time to check for timed out addresses
+	now, err := acc.setts.clock.Now()
+	if err != nil {
+		return 0, nil, nil, errors.Wrap(err, "unable to get time for doing input selection")
+	}
+
+	type selection struct {
+		keyIndex uint64
+		req      *store.StoredDepositRequest
+	}
+
+	// primary addresses to use to try to use to fulfill the transfer value
+	primaryAddrs := Hashes{}
+	primarySelection := []selection{}
+
+	// secondary addresses which are only used to fulfill the transfer
+	// if the primary addresses couldn't fund the transfer.
+	// the reason for this is that timed out addresses must be checked
+	// for incoming consistent transfers, which is a slow operation.
+	secondaryAddrs := Hashes{}
+	secondarySelection := []selection{}
+
+	// addresses/indices to remove from the store
+	toRemove := []uint64{}
+	var toRemoveMu sync.Mutex
+
+	markForRemoval := func(keyIndex uint64) {
+		if balanceCheck {
+			return
+		}
+		toRemoveMu.Lock()
+		toRemove = append(toRemove, keyIndex)
+		toRemoveMu.Unlock()
+	}
+
+	seed, err := acc.setts.seedProv.Seed()
+	if err != nil {
+		return 0, nil, nil, errors.Wrap(err, "unable to get seed from seed provider for doing input selection")
+	}
+
+	// indices earmarked by an active reservation must not be selected as inputs,
+	// nor counted towards the available balance, as they are already spoken for
+	earmarked := acc.earmarkedKeyIndices()
+
+	// iterate over all allocated deposit addresses
+	for keyIndex, req := range depositRequests {
+		if _, isEarmarked := earmarked[keyIndex]; isEarmarked {
+			continue
+		}
+
+		// remainder address
+		if req.TimeoutAt == nil {
+			if req.ExpectedAmount == nil {
+				panic("remainder address in system without 'expected amount'")
+			}
+			addr, _ := address.GenerateAddress(seed, keyIndex, req.SecurityLevel, false)
+			primaryAddrs = append(primaryAddrs, addr)
+			primarySelection = append(primarySelection, selection{keyIndex, req})
+			continue
+		}
+
+		// timed out
+		if now.After(*req.TimeoutAt) {
+			addr, _ := address.GenerateAddress(seed, keyIndex, req.SecurityLevel, false)
+			secondaryAddrs = append(secondaryAddrs, addr)
+			secondarySelection = append(secondarySelection, selection{keyIndex, req})
+			continue
+		}
+
+		// multi
+		if req.MultiUse {
+			// multi use deposit addresses are only used
+			// when they are timed out, if they don't define an expected amount
+			if req.ExpectedAmount == nil {
+				continue
+			}
+			addr, _ := address.GenerateAddress(seed, keyIndex, req.SecurityLevel, false)
+			primaryAddrs = append(primaryAddrs, addr)
+			primarySelection = append(primarySelection, selection{keyIndex, req})
+			continue
+		}
+
+		// single
+		addr, _ := address.GenerateAddress(seed, keyIndex, req.SecurityLevel, false)
+		primaryAddrs = append(primaryAddrs, addr)
+		primarySelection = append(primarySelection, selection{keyIndex, req})
+	}
+
+	// get the balance of all addresses (also secondary) in one go, fanned out across batches
+	toQuery := append(primaryAddrs, secondaryAddrs...)
+	balances, err := queryBalancesParallel(ctx, acc, toQuery, subtangleHash)
+	if err != nil {
+		return 0, nil, nil, errors.Wrap(err, "unable to fetch balances of primary selected addresses for input selection")
+	}
+
+	var inputsMu sync.Mutex
+	inputs := []api.Input{}
+	addAsInput := func(input *api.Input) {
+		if balanceCheck {
+			return
+		}
+		inputsMu.Lock()
+		inputs = append(inputs, *input)
+		inputsMu.Unlock()
+	}
+
+	// add addresses as inputs which fulfill their criteria
+	var sum uint64
+	for i := range primarySelection {
+		s := &primarySelection[i]
+		// skip addresses which have an expected amount which isn't reached however
+		if s.req.ExpectedAmount != nil && balances.Balances[i] < *s.req.ExpectedAmount {
+			continue
+		}
+		sum += balances.Balances[i]
+
+		// add the address as an input
+		if balances.Balances[i] <= 0 {
+			continue
+		}
+		addAsInput(&api.Input{
+			Address:  primaryAddrs[i],
+			KeyIndex: s.keyIndex,
+			Balance:  balances.Balances[i],
+			Security: s.req.SecurityLevel,
+		})
+
+		// mark the address for removal as it should be freed from the store
+		markForRemoval(s.keyIndex)
+		if sum > transferValue && !balanceCheck {
+			break
+		}
+	}
+
+	// if we didn't fulfill the transfer value,
+	// lets use the timed out addresses too to try to fulfill the transfer
+	if sum < transferValue || balanceCheck {
+		startPosSecondary := len(primarySelection)
+
+		sum, err = acc.selectSecondary(ctx, secondarySelection, secondaryAddrs, balances.Balances[startPosSecondary:],
+			sum, transferValue, balanceCheck, markForRemoval, addAsInput)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+	}
+
+	if balanceCheck {
+		return sum, nil, nil, nil
+	}
+
+	if sum < transferValue {
+		return 0, nil, nil, consts.ErrInsufficientBalance
+	}
+	return sum, inputs, toRemove, nil
+}
+
+// selectSecondary checks the timed out, secondary deposit addresses for incoming
+// consistent transfers in parallel through a Settings.SelectionConcurrency worker
+// pool, short-circuiting further consistency checks once sum exceeds transferValue.
+func (acc *account) selectSecondary(
+	ctx context.Context,
+	secondarySelection []struct {
+		keyIndex uint64
+		req      *store.StoredDepositRequest
+	},
+	secondaryAddrs Hashes,
+	secondaryBalances []uint64,
+	sum uint64,
+	transferValue uint64,
+	balanceCheck bool,
+	markForRemoval func(uint64),
+	addAsInput func(*api.Input),
+) (uint64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var firstErr error
+	sem := make(chan struct{}, acc.setts.concurrency())
+	var wg sync.WaitGroup
+
+	for i := range secondarySelection {
+		select {
+		case <-ctx.Done():
+		default:
+		}
+
+		i := i
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			secSelect := secondarySelection[i]
+			addr := secondaryAddrs[i]
+			balance := secondaryBalances[i]
+
+			if balance == 0 {
+				// check whether the timed out address has an incoming consistent value transfer,
+				// and if so, don't remove it from the store
+				has, err := acc.hasIncomingConsistentValueTransfer(ctx, addr)
+				if err != nil {
+					// the check was cancelled because another goroutine already fulfilled
+					// transferValue, not because we actually determined addr is unfunded:
+					// it must not be treated as "no transfer" and freed from the store
+					if err == context.Canceled {
+						return
+					}
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					return
+				}
+				if has {
+					return
+				}
+				markForRemoval(secSelect.keyIndex)
+				return
+			}
+
+			markForRemoval(secSelect.keyIndex)
+			addAsInput(&api.Input{
+				KeyIndex: secSelect.keyIndex,
+				Address:  addr,
+				Security: secSelect.req.SecurityLevel,
+				Balance:  balance,
+			})
+
+			mu.Lock()
+			sum += balance
+			if sum > transferValue && !balanceCheck {
+				cancel()
+			}
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	return sum, nil
+}
+
+// queryBalancesParallel fetches balances for the given addresses, splitting the
+// query into batches of defaultBalanceQueryBatchSize addresses and issuing them
+// concurrently through the account's selection concurrency worker pool. Results
+// are written back in the original address order, so a single failing batch no
+// longer stalls balance lookups for every other batch.
+func queryBalancesParallel(ctx context.Context, acc *account, addrs Hashes, subtangleHash Hash) (*api.Balances, error) {
+	if len(addrs) == 0 {
+		return &api.Balances{}, nil
+	}
+
+	type batch struct {
+		start int
+		addrs Hashes
+	}
+
+	var batches []batch
+	for i := 0; i < len(addrs); i += defaultBalanceQueryBatchSize {
+		end := i + defaultBalanceQueryBatchSize
+		if end > len(addrs) {
+			end = len(addrs)
+		}
+		batches = append(batches, batch{start: i, addrs: addrs[i:end]})
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]uint64, len(addrs))
+	sem := make(chan struct{}, acc.setts.concurrency())
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	for _, b := range batches {
+		b := b
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			balances, err := acc.setts.api.GetBalances(b.addrs, 100, subtangleHash)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				cancel()
+				return
+			}
+			copy(results[b.start:], balances.Balances)
+		}()
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return &api.Balances{Balances: results}, nil
+}
+
+// hasIncomingConsistentValueTransfer checks whether addr has a pending, consistent
+// incoming value transfer, bailing out early if ctx is cancelled (e.g. because the
+// parent selection already found enough funds elsewhere). A cancellation is reported
+// back as context.Canceled rather than (false, nil): the caller must not treat it as
+// a verified absence of an incoming transfer.
+func (acc *account) hasIncomingConsistentValueTransfer(ctx context.Context, addr Hash) (bool, error) {
+	select {
+	case <-ctx.Done():
+		return false, context.Canceled
+	default:
+	}
+
+	var has bool
+	bndls, err := acc.setts.api.GetBundlesFromAddresses(Hashes{addr}, true)
+	if err != nil {
+		return false, err
+	}
+	persisted := map[string]struct{}{}
+	for i := range bndls {
+		if *(bndls[i][0]).Persistence {
+			persisted[bndls[i][0].Bundle] = struct{}{}
+			continue
+		}
+
+		// skip reattachments of an already persisted bundle
+		if _, has := persisted[bndls[i][0].Bundle]; has {
+			continue
+		}
+
+		// check whether it's even a deposit to the address we are checking
+		var isDepositToAddr bool
+		for j := range bndls[i] {
+			if bndls[i][j].Value > 0 && bndls[i][j].Address == addr {
+				isDepositToAddr = true
+				break
+			}
+		}
+
+		// ignore this transfer as it isn't an incoming value transfer
+		if !isDepositToAddr {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, context.Canceled
+		default:
+		}
+
+		// here we have a bundle which is not yet confirmed
+		// and is depositing something onto this address.
+		// lets check it for its consistency
+		hash := bndls[i][0].Hash
+		consistent, _, err := acc.setts.api.CheckConsistency(hash)
+		if err != nil {
+			return false, errors.Wrapf(err, "unable to check consistency of tx %s in incoming consistent transfer check", hash)
+		}
+		if consistent {
+			has = true
+			break
+		}
+	}
+	return has, nil
+}
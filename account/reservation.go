@@ -0,0 +1,193 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/iotaledger/iota.go/account/deposit"
+	"github.com/iotaledger/iota.go/account/event"
+	"github.com/iotaledger/iota.go/api"
+	"github.com/iotaledger/iota.go/bundle"
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/guards"
+	. "github.com/iotaledger/iota.go/trinary"
+	"github.com/pkg/errors"
+)
+
+// ReservationID identifies a fund reservation made via Account.Reserve().
+type ReservationID string
+
+// reservation holds the inputs earmarked for a single Reserve() call.
+// While a reservation is active, its inputs are excluded from input selection
+// performed on behalf of other Send/AvailableBalance calls.
+type reservation struct {
+	id        ReservationID
+	amount    uint64
+	inputs    []api.Input
+	expiresAt time.Time
+}
+
+// ErrReservationNotFound gets returned when a given ReservationID does not correspond to an active reservation.
+var ErrReservationNotFound = errors.New("reservation not found")
+
+// ErrReservationTimeoutTooLow gets returned when a reservation's timeout is zero or negative.
+var ErrReservationTimeoutTooLow = errors.New("reservation timeout must be greater than zero")
+
+func (acc *account) Reserve(amount uint64, timeout time.Duration) (ReservationID, error) {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if !acc.running {
+		return "", ErrAccountNotRunning
+	}
+	if timeout <= 0 {
+		return "", ErrReservationTimeoutTooLow
+	}
+
+	sum, inputs, _, err := acc.setts.inputSelectionStrategy(context.Background(), acc, amount, false)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to perform input selection in reserve op.")
+	}
+
+	currentTime, err := acc.setts.clock.Now()
+	if err != nil {
+		return "", errors.Wrap(err, "unable to get current time in reserve op.")
+	}
+
+	acc.resMu.Lock()
+	acc.reservationSeq++
+	id := ReservationID(fmt.Sprintf("%s-%d", acc.id, acc.reservationSeq))
+	acc.reservations[id] = &reservation{
+		id:        id,
+		amount:    sum,
+		inputs:    inputs,
+		expiresAt: currentTime.Add(timeout),
+	}
+	acc.resMu.Unlock()
+
+	return id, nil
+}
+
+func (acc *account) Release(id ReservationID) error {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if !acc.running {
+		return ErrAccountNotRunning
+	}
+
+	acc.resMu.Lock()
+	defer acc.resMu.Unlock()
+	if _, has := acc.reservations[id]; !has {
+		return ErrReservationNotFound
+	}
+	delete(acc.reservations, id)
+	return nil
+}
+
+func (acc *account) SendWithReservation(id ReservationID, recipients ...Recipient) (bundle.Bundle, error) {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if !acc.running {
+		return nil, ErrAccountNotRunning
+	}
+	if recipients == nil || len(recipients) == 0 {
+		return nil, ErrEmptyRecipients
+	}
+	for _, target := range recipients {
+		if !guards.IsTrytesOfExactLength(target.Address, consts.HashTrytesSize+consts.AddressChecksumTrytesSize) {
+			return nil, consts.ErrInvalidAddress
+		}
+	}
+
+	acc.resMu.Lock()
+	res, has := acc.reservations[id]
+	if has {
+		delete(acc.reservations, id)
+	}
+	acc.resMu.Unlock()
+	if !has {
+		return nil, ErrReservationNotFound
+	}
+
+	targets := Recipients(recipients)
+	transferSum := targets.Sum()
+
+	var remainderAddress *Hash
+	if res.amount > transferSum {
+		remainder := res.amount - transferSum
+		depCond, err := acc.allocateDepositRequest(&deposit.Request{ExpectedAmount: &remainder})
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to generate remainder address in send with reservation op.")
+		}
+		remainderAddress = &depCond.Address
+	}
+
+	forRemoval := make([]uint64, len(res.inputs))
+	for i, input := range res.inputs {
+		forRemoval[i] = input.KeyIndex
+	}
+
+	return acc.sendWithInputs(targets, res.inputs, remainderAddress, forRemoval)
+}
+
+// earmarkedKeyIndices returns the set of key indices currently tied up in an active reservation.
+func (acc *account) earmarkedKeyIndices() map[uint64]struct{} {
+	acc.resMu.Lock()
+	defer acc.resMu.Unlock()
+
+	earmarked := make(map[uint64]struct{})
+	for _, res := range acc.reservations {
+		for _, input := range res.inputs {
+			earmarked[input.KeyIndex] = struct{}{}
+		}
+	}
+	return earmarked
+}
+
+// reservationExpiryLoop periodically releases reservations which have passed their timeout,
+// re-emitting their inputs back into the pool so they become selectable again.
+func (acc *account) reservationExpiryLoop(quit chan struct{}) {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+			acc.expireReservations()
+		}
+	}
+}
+
+func (acc *account) expireReservations() {
+	// acc.setts can be swapped out concurrently by UpdateSettings, so take a
+	// read lock while snapshotting the clock/eventMachine it points to, same
+	// as any other background goroutine touching acc.setts.
+	acc.mu.RLock()
+	clock := acc.setts.clock
+	eventMachine := acc.setts.eventMachine
+	acc.mu.RUnlock()
+
+	currentTime, err := clock.Now()
+	if err != nil {
+		return
+	}
+
+	var expired []*reservation
+	acc.resMu.Lock()
+	for id, res := range acc.reservations {
+		if currentTime.After(res.expiresAt) {
+			expired = append(expired, res)
+			delete(acc.reservations, id)
+		}
+	}
+	acc.resMu.Unlock()
+
+	for _, res := range expired {
+		eventMachine.Emit(res.id, event.EventReservationExpired)
+	}
+}
@@ -0,0 +1,165 @@
+package account
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/iotaledger/iota.go/account/event"
+	"github.com/iotaledger/iota.go/account/store"
+	"github.com/iotaledger/iota.go/api"
+	"github.com/iotaledger/iota.go/consts"
+	. "github.com/iotaledger/iota.go/trinary"
+)
+
+// Clock abstracts access to the current time, making the account's timeout/expiry
+// logic deterministic and testable.
+type Clock interface {
+	Now() (time.Time, error)
+}
+
+// SeedProvider supplies the seed used for address derivation and signing.
+type SeedProvider interface {
+	Seed() (Trytes, error)
+}
+
+// Plugin is a long-running task driven by the account's event loop, for example
+// promotion/reattachment or incoming transfer polling.
+type Plugin interface {
+	Start(acc Account) error
+	Shutdown() error
+}
+
+// InputSelectionFunc selects deposit addresses as inputs for a transfer.
+type InputSelectionFunc func(ctx context.Context, acc *account, transferValue uint64, balanceCheck bool) (uint64, []api.Input, []uint64, error)
+
+// Settings defines the configuration of an Account.
+type Settings struct {
+	api                    *api.API
+	store                  store.Store
+	clock                  Clock
+	eventMachine           event.EventMachine
+	mwm                    uint64
+	depth                  uint64
+	securityLevel          consts.SecurityLevel
+	seedProv               SeedProvider
+	signer                 Signer
+	inputSelectionStrategy InputSelectionFunc
+	plugins                []Plugin
+	// selectionConcurrency bounds how many balance/consistency queries
+	// defaultInputSelection runs against the node at once.
+	selectionConcurrency int
+}
+
+// Setting mutates a Settings instance.
+type Setting func(*Settings)
+
+// defaultSettings returns a Settings populated with the repo's defaults.
+func defaultSettings() *Settings {
+	return &Settings{
+		mwm:                    14,
+		depth:                  3,
+		securityLevel:          consts.SecurityLevelMedium,
+		inputSelectionStrategy: defaultInputSelection,
+		selectionConcurrency:   runtime.GOMAXPROCS(0),
+	}
+}
+
+// NewSettings creates a new Settings instance from the given options.
+func NewSettings(setts ...Setting) *Settings {
+	defaultSetts := defaultSettings()
+	for _, set := range setts {
+		set(defaultSetts)
+	}
+	if defaultSetts.signer == nil && defaultSetts.seedProv != nil {
+		defaultSetts.signer = NewSeedProviderSigner(defaultSetts.seedProv)
+	}
+	return defaultSetts
+}
+
+// WithSeedProvider defines the used SeedProvider.
+func WithSeedProvider(seedProv SeedProvider) Setting {
+	return func(setts *Settings) {
+		setts.seedProv = seedProv
+	}
+}
+
+// WithSigner defines the Signer used to derive deposit addresses. Sending works
+// only with a SeedProviderSigner (see ErrSignerNotSupported). When not set, a SeedProviderSigner wrapping the configured SeedProvider is used.
+func WithSigner(signer Signer) Setting {
+	return func(setts *Settings) {
+		setts.signer = signer
+	}
+}
+
+// WithAPI defines the used API instance.
+func WithAPI(api *api.API) Setting {
+	return func(setts *Settings) {
+		setts.api = api
+	}
+}
+
+// WithStore defines the used Store instance.
+func WithStore(store store.Store) Setting {
+	return func(setts *Settings) {
+		setts.store = store
+	}
+}
+
+// WithClock defines the used Clock instance.
+func WithClock(clock Clock) Setting {
+	return func(setts *Settings) {
+		setts.clock = clock
+	}
+}
+
+// WithEventMachine defines the used event.EventMachine instance.
+func WithEventMachine(eventMachine event.EventMachine) Setting {
+	return func(setts *Settings) {
+		setts.eventMachine = eventMachine
+	}
+}
+
+// WithDepth defines the depth for doing tip selection.
+func WithDepth(depth uint64) Setting {
+	return func(setts *Settings) {
+		setts.depth = depth
+	}
+}
+
+// WithMWM defines the minimum weight magnitude used for doing PoW.
+func WithMWM(mwm uint64) Setting {
+	return func(setts *Settings) {
+		setts.mwm = mwm
+	}
+}
+
+// WithSecurityLevel defines the used security level for generating addresses.
+func WithSecurityLevel(lvl consts.SecurityLevel) Setting {
+	return func(setts *Settings) {
+		setts.securityLevel = lvl
+	}
+}
+
+// WithInputSelectionStrategy defines the used input selection strategy.
+func WithInputSelectionStrategy(strategy InputSelectionFunc) Setting {
+	return func(setts *Settings) {
+		setts.inputSelectionStrategy = strategy
+	}
+}
+
+// WithPlugin adds the given plugin to the account.
+func WithPlugin(plugin Plugin) Setting {
+	return func(setts *Settings) {
+		setts.plugins = append(setts.plugins, plugin)
+	}
+}
+
+// WithSelectionConcurrency defines the maximum number of concurrent
+// balance/consistency queries defaultInputSelection issues against the node.
+// Defaults to runtime.GOMAXPROCS(0).
+func WithSelectionConcurrency(concurrency int) Setting {
+	return func(setts *Settings) {
+		setts.selectionConcurrency = concurrency
+	}
+}
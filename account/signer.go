@@ -0,0 +1,77 @@
+package account
+
+import (
+	"github.com/iotaledger/iota.go/address"
+	"github.com/iotaledger/iota.go/api"
+	"github.com/iotaledger/iota.go/bundle"
+	"github.com/iotaledger/iota.go/consts"
+	. "github.com/iotaledger/iota.go/trinary"
+	"github.com/pkg/errors"
+)
+
+// Signer abstracts signing and address derivation away from direct seed access.
+// Deposit address allocation, TotalBalance and MonitoredAddresses derive
+// addresses through DeriveAddress. Sending works only with the default
+// SeedProviderSigner: prepareAndSign returns ErrSignerNotSupported for any other
+// Signer. Account creation and input selection read the seed from the
+// SeedProvider directly.
+type Signer interface {
+	// SignInputs signs the given unsigned bundle essence using the given inputs
+	// and returns the fully signed bundle trytes.
+	SignInputs(bundleEssence Trytes, inputs []api.Input) (Trytes, error)
+	// DeriveAddress derives the deposit address for the given key index and security level.
+	DeriveAddress(keyIndex uint64, securityLevel consts.SecurityLevel) (Hash, error)
+}
+
+// ErrSignerNotSupported gets returned by prepareAndSign when the account's
+// configured Signer is anything other than the default SeedProviderSigner,
+// since api does not yet expose the essence/SignInputs split a custom Signer
+// would need.
+var ErrSignerNotSupported = errors.New("only the default SeedProviderSigner is supported until api exposes PrepareTransfersEssence")
+
+// SeedProviderSigner is the default Signer, deriving addresses and signing bundles
+// in-process from a seed obtained via a SeedProvider. It keeps accounts which only
+// configure a SeedProvider, as before this feature, working unmodified.
+type SeedProviderSigner struct {
+	seedProv SeedProvider
+}
+
+// NewSeedProviderSigner wraps the given SeedProvider as a Signer.
+func NewSeedProviderSigner(seedProv SeedProvider) *SeedProviderSigner {
+	return &SeedProviderSigner{seedProv: seedProv}
+}
+
+// DeriveAddress derives the address for keyIndex/securityLevel directly from the seed.
+func (s *SeedProviderSigner) DeriveAddress(keyIndex uint64, securityLevel consts.SecurityLevel) (Hash, error) {
+	seed, err := s.seedProv.Seed()
+	if err != nil {
+		return "", err
+	}
+	return address.GenerateAddress(seed, keyIndex, securityLevel, false)
+}
+
+// SignInputs is unused for the default signer: prepareAndSign() routes it
+// through the existing single-call api.PrepareTransfers instead, since the
+// api package this series ships against has no essence/SignInputs split to
+// plug into. It is implemented only to satisfy the Signer interface.
+func (s *SeedProviderSigner) SignInputs(bundleEssence Trytes, inputs []api.Input) (Trytes, error) {
+	return "", ErrSignerNotSupported
+}
+
+// prepareAndSign prepares the transfer bundle and signs it, using the account's
+// configured Signer. The default SeedProviderSigner is routed through the
+// existing api.PrepareTransfers, which already signs with the seed
+// internally; any other Signer would need a PrepareTransfersEssence/SignInputs
+// split that api does not yet expose.
+func (acc *account) prepareAndSign(transfers bundle.Transfers, opts api.PrepareTransfersOptions, inputs []api.Input) (Trytes, error) {
+	sps, ok := acc.setts.signer.(*SeedProviderSigner)
+	if !ok {
+		return "", ErrSignerNotSupported
+	}
+
+	seed, err := sps.seedProv.Seed()
+	if err != nil {
+		return "", err
+	}
+	return acc.setts.api.PrepareTransfers(seed, transfers, opts)
+}
@@ -0,0 +1,71 @@
+package store
+
+import "github.com/pkg/errors"
+
+// Migration upgrades a store from one schema version to the next.
+// Migrations are applied in ascending Version() order by Account.Start()
+// under a write lock, so a store is never read from or written to mid-upgrade.
+type Migration interface {
+	// Version is the schema version this migration upgrades the store to.
+	Version() uint32
+	// Up applies the migration to the given store.
+	Up(Store) error
+}
+
+var registeredMigrations []Migration
+
+// ErrStoreVersionNewerThanCode gets returned when a store's on-disk schema
+// version is newer than any migration known to this version of the code.
+var ErrStoreVersionNewerThanCode = errors.New("store schema version is newer than this version of the code supports")
+
+// RegisterMigration registers a migration to be applied during Account.Start().
+// Migrations must be registered in ascending Version() order; RegisterMigration
+// panics otherwise, since an out-of-order migration is almost certainly a bug
+// in the migration itself rather than something a caller intended.
+func RegisterMigration(m Migration) {
+	if len(registeredMigrations) > 0 {
+		if last := registeredMigrations[len(registeredMigrations)-1].Version(); m.Version() <= last {
+			panic("store: migrations must be registered in ascending Version() order")
+		}
+	}
+	registeredMigrations = append(registeredMigrations, m)
+}
+
+// CurrentSchemaVersion returns the schema version the latest registered
+// migration upgrades a store to.
+func CurrentSchemaVersion() uint32 {
+	if len(registeredMigrations) == 0 {
+		return 0
+	}
+	return registeredMigrations[len(registeredMigrations)-1].Version()
+}
+
+// Migrate reads the given store's current schema version and applies any
+// pending, registered migrations in ascending order, persisting the schema
+// version after each successfully applied step. It refuses to run if the
+// store's version is newer than CurrentSchemaVersion, since that means the
+// store was last touched by a newer version of the code.
+func Migrate(s Store) error {
+	version, err := s.SchemaVersion()
+	if err != nil {
+		return errors.Wrap(err, "unable to read schema version")
+	}
+
+	if version > CurrentSchemaVersion() {
+		return ErrStoreVersionNewerThanCode
+	}
+
+	for _, m := range registeredMigrations {
+		if m.Version() <= version {
+			continue
+		}
+		if err := m.Up(s); err != nil {
+			return errors.Wrapf(err, "unable to apply migration to schema version %d", m.Version())
+		}
+		if err := s.SetSchemaVersion(m.Version()); err != nil {
+			return errors.Wrapf(err, "unable to persist schema version %d", m.Version())
+		}
+	}
+
+	return nil
+}
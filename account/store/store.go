@@ -0,0 +1,73 @@
+package store
+
+import (
+	"github.com/iotaledger/iota.go/account/deposit"
+	"github.com/iotaledger/iota.go/consts"
+	. "github.com/iotaledger/iota.go/trinary"
+)
+
+// StoredDepositRequest is the on-disk representation of a deposit.Request,
+// annotated with the security level its address was generated with.
+type StoredDepositRequest struct {
+	SecurityLevel consts.SecurityLevel
+	deposit.Request
+}
+
+// StoredWatchedAddress is the on-disk representation of an address imported
+// via Account.WatchAddress.
+type StoredWatchedAddress struct {
+	IncludeInBalance  bool
+	HasExternalSigner bool
+}
+
+// AccountState is a snapshot of everything Store persists about a single account.
+type AccountState struct {
+	KeyIndex        uint64
+	DepositRequests map[uint64]*StoredDepositRequest
+}
+
+// IsNew reports whether this is the zero-value state of an account which has
+// never allocated a deposit address before.
+func (s *AccountState) IsNew() bool {
+	return s.KeyIndex == 0 && len(s.DepositRequests) == 0
+}
+
+// Store persists account state: allocated key indices, deposit requests,
+// pending transfers, watched addresses, and the schema version migrations
+// are applied against. Implementations must make Add*/Write* calls safe for
+// concurrent use by a single account.
+type Store interface {
+	LoadAccount(id string) (*AccountState, error)
+	WriteIndex(id string, index uint64) error
+
+	AddDepositRequest(id string, index uint64, req *StoredDepositRequest) error
+	// GetDepositRequests returns id's allocated deposit requests. From schema
+	// version 2 onward (see migrationV2CompactDepositRequests), a conforming
+	// implementation must transparently decode the CBOR blob persisted by
+	// WriteCompactDepositRequests instead of reading the pre-migration
+	// per-field encoding; callers never see the difference.
+	GetDepositRequests(id string) (map[uint64]*StoredDepositRequest, error)
+
+	AddPendingTransfer(id string, tailTxHash Hash, trytes []Trytes, indices ...uint64) error
+
+	AddWatchedAddress(id string, addr Hash, watched *StoredWatchedAddress) error
+	RemoveWatchedAddress(id string, addr Hash) error
+	WatchedAddresses(id string) (map[Hash]*StoredWatchedAddress, error)
+
+	// AccountIDs lists every account this store holds state for, so that
+	// migrations can walk every account without the caller knowing the set
+	// of account IDs up front.
+	AccountIDs() ([]string, error)
+
+	// SchemaVersion returns the schema version the store is currently at,
+	// or 0 for a store that predates the migration framework.
+	SchemaVersion() (uint32, error)
+	// SetSchemaVersion persists the store's schema version after a migration
+	// has been applied.
+	SetSchemaVersion(version uint32) error
+
+	// WriteCompactDepositRequests persists the CBOR-encoded deposit request
+	// map produced by migrationV2CompactDepositRequests; GetDepositRequests
+	// decodes it back when present, instead of the pre-migration per-field encoding.
+	WriteCompactDepositRequests(id string, compact []byte) error
+}
@@ -0,0 +1,140 @@
+package account
+
+import (
+	"github.com/iotaledger/iota.go/account/store"
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/guards"
+	. "github.com/iotaledger/iota.go/trinary"
+	"github.com/pkg/errors"
+)
+
+// ErrWatchedAddressNotFound gets returned when a given address is not currently watched.
+var ErrWatchedAddressNotFound = errors.New("watched address not found")
+
+// ExternalSigner lets a watch-only address supply signatures out-of-band, since the
+// account does not hold a seed-derived private key for addresses it merely watches.
+// This is the extension point for hardware signers or multi-sig coordinators.
+type ExternalSigner interface {
+	// SignBundle signs the given bundle essence on behalf of the watched address
+	// and returns the signed transaction trytes.
+	SignBundle(addr Hash, bundleEssence Trytes) (Trytes, error)
+}
+
+// WatchOptions defines the parameters of a watched, externally-owned or previously
+// generated deposit address.
+type WatchOptions struct {
+	// IncludeInBalance defines whether the watched address' balance is included in
+	// TotalBalance()/AvailableBalance(). Opt-in, since a watched address' funds are
+	// not necessarily spendable by this account.
+	IncludeInBalance bool
+	// Signer is an optional out-of-band signer for this watched address, for
+	// example a hardware wallet. WatchAddress records only whether one was
+	// supplied; the account neither keeps nor calls it.
+	Signer ExternalSigner
+}
+
+// WatchAddress starts tracking the given address as a watch-only deposit address.
+// Its key index is not derived from this account's seed, therefore input
+// selection never selects it as an input, whether or not opts carries a Signer.
+func (acc *account) WatchAddress(addr Hash, opts WatchOptions) error {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if !acc.running {
+		return ErrAccountNotRunning
+	}
+
+	if !guards.IsTrytesOfExactLength(addr, consts.HashTrytesSize) {
+		return consts.ErrInvalidAddress
+	}
+
+	return acc.setts.store.AddWatchedAddress(acc.id, addr, &store.StoredWatchedAddress{
+		IncludeInBalance:  opts.IncludeInBalance,
+		HasExternalSigner: opts.Signer != nil,
+	})
+}
+
+// UnwatchAddress stops tracking the given address.
+func (acc *account) UnwatchAddress(addr Hash) error {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if !acc.running {
+		return ErrAccountNotRunning
+	}
+
+	return acc.setts.store.RemoveWatchedAddress(acc.id, addr)
+}
+
+// MonitoredAddresses returns every address this account should be watched
+// for incoming transfers on: its allocated deposit addresses plus any
+// addresses added via WatchAddress. It lets incoming-transfer poller plugins
+// registered via WithPlugin poll watched addresses as well as deposit
+// addresses; the account itself does not call it.
+func (acc *account) MonitoredAddresses() (Hashes, error) {
+	acc.mu.RLock()
+	defer acc.mu.RUnlock()
+
+	state, err := acc.setts.store.LoadAccount(acc.id)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load account state for computing monitored addresses")
+	}
+
+	addrs := make(Hashes, 0, len(state.DepositRequests))
+	for keyIndex, req := range state.DepositRequests {
+		addr, err := acc.setts.signer.DeriveAddress(keyIndex, req.SecurityLevel)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+
+	watched, err := acc.setts.store.WatchedAddresses(acc.id)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load watched addresses for computing monitored addresses")
+	}
+	for addr := range watched {
+		addrs = append(addrs, addr)
+	}
+
+	return addrs, nil
+}
+
+// watchedAddressesBalance sums up the balance of all watched addresses which
+// opted into being reflected in the account's balance.
+func (acc *account) watchedAddressesBalance() (uint64, error) {
+	watched, err := acc.setts.store.WatchedAddresses(acc.id)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to load watched addresses")
+	}
+	if len(watched) == 0 {
+		return 0, nil
+	}
+
+	addrs := make(Hashes, 0, len(watched))
+	for addr, w := range watched {
+		if !w.IncludeInBalance {
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	if len(addrs) == 0 {
+		return 0, nil
+	}
+
+	solidSubtangleMilestone, err := acc.setts.api.GetLatestSolidSubtangleMilestone()
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to fetch latest solid subtangle milestone for watched address balance")
+	}
+
+	balances, err := acc.setts.api.GetBalances(addrs, 100, solidSubtangleMilestone.LatestSolidSubtangleMilestone)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to fetch balances of watched addresses")
+	}
+
+	var sum uint64
+	for _, balance := range balances.Balances {
+		sum += balance
+	}
+	return sum, nil
+}
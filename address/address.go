@@ -5,6 +5,7 @@ import (
 	"github.com/iotaledger/giota/checksum"
 	. "github.com/iotaledger/giota/signing"
 	. "github.com/iotaledger/giota/trinary"
+	"sync"
 )
 
 // Error types for address
@@ -71,6 +72,68 @@ func GenerateAddresses(seed Trytes, start uint64, count uint64, secLvl SecurityL
 	return addresses, nil
 }
 
+// GenerateAddressesParallel generates N new addresses from the given seed, indices and
+// security level, splitting the work across workers goroutines. Output order matches
+// GenerateAddresses; each index i writes only to addresses[i], so no locking is needed
+// beyond waiting for every goroutine to finish.
+func GenerateAddressesParallel(seed Trytes, start uint64, count uint64, secLvl SecurityLevel, workers int, addChecksum ...bool) (Hashes, error) {
+	if count == 0 {
+		return Hashes{}, nil
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+	if uint64(workers) > count {
+		workers = int(count)
+	}
+
+	var withChecksum bool
+	if len(addChecksum) > 0 && addChecksum[0] {
+		withChecksum = true
+	}
+
+	addresses := make(Hashes, count)
+	errCh := make(chan error, workers)
+	var wg sync.WaitGroup
+
+	chunkSize := (count + uint64(workers) - 1) / uint64(workers)
+	for w := 0; w < workers; w++ {
+		from := uint64(w) * chunkSize
+		if from >= count {
+			break
+		}
+		to := from + chunkSize
+		if to > count {
+			to = count
+		}
+
+		wg.Add(1)
+		go func(from, to uint64) {
+			defer wg.Done()
+			for i := from; i < to; i++ {
+				addr, err := GenerateAddress(seed, start+i, secLvl, withChecksum)
+				if err != nil {
+					select {
+					case errCh <- err:
+					default:
+					}
+					return
+				}
+				addresses[i] = addr
+			}
+		}(from, to)
+	}
+
+	wg.Wait()
+	close(errCh)
+	if err, has := <-errCh; has {
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
 // ValidAddressHash checks whether the given address is valid.
 func ValidAddressHash(a Hash) error {
 	if !(len(a) == 81) {
@@ -0,0 +1,44 @@
+package address
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	. "github.com/iotaledger/giota/signing"
+	. "github.com/iotaledger/giota/trinary"
+)
+
+const benchSeed Trytes = "NOPQRSTUVWXYZ9ABCDEFGHIJKLMNOPQRSTUVWXYZ9ABCDEFGHIJKLMNOPQRSTUVWXYZ9ABCDEFGHIJ"
+
+var benchSecLvls = []SecurityLevel{SecurityLevelLow, SecurityLevelMedium, SecurityLevelHigh}
+var benchCounts = []uint64{1, 16, 256}
+
+// BenchmarkGenerateAddresses compares the serial and parallel address generators
+// across every combination of security level and address count named in the
+// chunk1-3 request.
+func BenchmarkGenerateAddresses(b *testing.B) {
+	for _, secLvl := range benchSecLvls {
+		for _, count := range benchCounts {
+			b.Run(benchName("serial", secLvl, count), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := GenerateAddresses(benchSeed, 0, count, secLvl); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			b.Run(benchName("parallel", secLvl, count), func(b *testing.B) {
+				workers := runtime.GOMAXPROCS(0)
+				for i := 0; i < b.N; i++ {
+					if _, err := GenerateAddressesParallel(benchSeed, 0, count, secLvl, workers); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func benchName(mode string, secLvl SecurityLevel, count uint64) string {
+	return fmt.Sprintf("%s/secLvl=%d/count=%d", mode, secLvl, count)
+}
@@ -0,0 +1,225 @@
+// Package merkle builds a binary Merkle tree on top of the package signing's
+// Winternitz one-time keys, turning a single seed+root into N usable
+// signatures. This is the foundation MAM-style channels and long-lived
+// identities are built on.
+package merkle
+
+import (
+	"errors"
+
+	"github.com/iotaledger/giota/curl"
+	. "github.com/iotaledger/giota/signing"
+	"github.com/iotaledger/giota/signing/sponge"
+	. "github.com/iotaledger/giota/trinary"
+)
+
+// ErrDepthTooLow gets returned when a tree of depth zero is requested; such a
+// tree would hold a single, non-authenticated leaf, which defeats the purpose
+// of building a Merkle tree in the first place.
+var ErrDepthTooLow = errors.New("merkle tree depth must be greater than zero")
+
+// Signature is a Winternitz signature extended with the Merkle authentication
+// path proving that its address is the leaf at Index of the tree with root Root.
+type Signature struct {
+	SignatureFragments []Trytes
+	Root               Trytes
+	Branch             Trytes
+	Index              uint64
+	SecurityLevel      SecurityLevel
+}
+
+// MerkleCreate builds a binary Merkle tree of 2^depth leaves, where leaf i is
+// Address(Digests(Key(Subseed(seed, offset+i), secLvl))), and returns its root
+// together with the full tree, stored level by level (leaves first) so that
+// MerkleBranch can later extract an authentication path for any leaf index.
+// An optional spongeFunc overrides the default Kerl sponge used both for the
+// leaves and for hashing the tree's internal nodes; MAM-style channels pass
+// sponge.NewCurlP27 here.
+func MerkleCreate(seed Trytes, depth uint64, offset uint64, secLvl SecurityLevel, spongeFunc ...func() sponge.SpongeFunction) (Trytes, Trits, error) {
+	if depth == 0 {
+		return "", nil, ErrDepthTooLow
+	}
+
+	newSponge := sponge.GetSpongeFunc(spongeFunc, sponge.NewKerl)
+
+	leafCount := uint64(1) << depth
+	totalNodes := (leafCount << 1) - 1
+	tree := make(Trits, totalNodes*curl.HashSize)
+
+	for i := uint64(0); i < leafCount; i++ {
+		subseed, err := Subseed(seed, offset+i, newSponge)
+		if err != nil {
+			return "", nil, err
+		}
+		key, err := Key(subseed, secLvl, newSponge)
+		if err != nil {
+			return "", nil, err
+		}
+		digests, err := Digests(key, newSponge)
+		if err != nil {
+			return "", nil, err
+		}
+		leaf, err := Address(digests, newSponge)
+		if err != nil {
+			return "", nil, err
+		}
+		copy(tree[i*uint64(curl.HashSize):], leaf)
+	}
+
+	k := newSponge()
+	levelStart := uint64(0)
+	levelSize := leafCount
+	for levelSize > 1 {
+		nextLevelStart := levelStart + levelSize*uint64(curl.HashSize)
+		for i := uint64(0); i < levelSize/2; i++ {
+			left := tree[levelStart+2*i*uint64(curl.HashSize) : levelStart+(2*i+1)*uint64(curl.HashSize)]
+			right := tree[levelStart+(2*i+1)*uint64(curl.HashSize) : levelStart+(2*i+2)*uint64(curl.HashSize)]
+
+			k.Reset()
+			if err := k.Absorb(left); err != nil {
+				return "", nil, err
+			}
+			if err := k.Absorb(right); err != nil {
+				return "", nil, err
+			}
+			parent, err := k.Squeeze(curl.HashSize)
+			if err != nil {
+				return "", nil, err
+			}
+			copy(tree[nextLevelStart+i*uint64(curl.HashSize):], parent)
+		}
+		levelStart = nextLevelStart
+		levelSize /= 2
+	}
+
+	root := MustTritsToTrytes(tree[levelStart : levelStart+uint64(curl.HashSize)])
+	return root, tree, nil
+}
+
+// MerkleBranch returns the authentication path for the leaf at leafIndex, by
+// concatenating its sibling hash at every level of tree, bottom to top.
+func MerkleBranch(tree Trits, depth uint64, leafIndex uint64) Trits {
+	branch := make(Trits, 0, depth*uint64(curl.HashSize))
+
+	levelStart := uint64(0)
+	levelSize := uint64(1) << depth
+	idx := leafIndex
+	for levelSize > 1 {
+		siblingIdx := idx ^ 1
+		sibling := tree[levelStart+siblingIdx*uint64(curl.HashSize) : levelStart+(siblingIdx+1)*uint64(curl.HashSize)]
+		branch = append(branch, sibling...)
+
+		levelStart += levelSize * uint64(curl.HashSize)
+		levelSize /= 2
+		idx /= 2
+	}
+	return branch
+}
+
+// MerkleRoot recomputes the root of a Merkle tree from a leaf hash and its
+// authentication path: for i=0..siblingsCount-1, it absorbs the i-th sibling
+// together with the current hash (sibling first if the current node is a
+// right child, i.e. bit i of leafIndex is 1; current hash first otherwise),
+// squeezes the next hash, resets the sponge, and shifts leafIndex by one bit.
+func MerkleRoot(leafHash Trits, siblings Trits, siblingsCount uint64, leafIndex uint64, spongeInst sponge.SpongeFunction) (Trits, error) {
+	hash := leafHash
+	for i := uint64(0); i < siblingsCount; i++ {
+		sibling := siblings[i*uint64(curl.HashSize) : (i+1)*uint64(curl.HashSize)]
+
+		spongeInst.Reset()
+		if leafIndex&1 == 1 {
+			if err := spongeInst.Absorb(sibling); err != nil {
+				return nil, err
+			}
+			if err := spongeInst.Absorb(hash); err != nil {
+				return nil, err
+			}
+		} else {
+			if err := spongeInst.Absorb(hash); err != nil {
+				return nil, err
+			}
+			if err := spongeInst.Absorb(sibling); err != nil {
+				return nil, err
+			}
+		}
+
+		squeezed, err := spongeInst.Squeeze(curl.HashSize)
+		if err != nil {
+			return nil, err
+		}
+		hash = squeezed
+		leafIndex >>= 1
+	}
+	return hash, nil
+}
+
+// MerkleSign signs bundleHash with the key fragment(s) derived from
+// Subseed(seed, leafIndex), and attaches the authentication path of leafIndex's
+// leaf in tree. leafIndex is used both as the seed index and as the position in
+// tree, so the signature verifies only for a tree MerkleCreate built with
+// offset 0. spongeFunc must match the one tree was built with.
+func MerkleSign(bundleHash Hash, seed Trytes, tree Trits, depth uint64, leafIndex uint64, secLvl SecurityLevel, spongeFunc ...func() sponge.SpongeFunction) (*Signature, error) {
+	newSponge := sponge.GetSpongeFunc(spongeFunc, sponge.NewKerl)
+
+	subseed, err := Subseed(seed, leafIndex, newSponge)
+	if err != nil {
+		return nil, err
+	}
+	key, err := Key(subseed, secLvl, newSponge)
+	if err != nil {
+		return nil, err
+	}
+
+	normalizedBundleHash := NormalizedBundleHash(bundleHash)
+	fragments := make([]Trytes, secLvl)
+	for i := 0; i < int(secLvl); i++ {
+		keyFragment := key[i*KeyFragmentLength : (i+1)*KeyFragmentLength]
+		sigFrag, err := SignatureFragment(normalizedBundleHash[(i%3)*27:(i%3+1)*27], keyFragment, newSponge)
+		if err != nil {
+			return nil, err
+		}
+		fragments[i] = MustTritsToTrytes(sigFrag)
+	}
+
+	root := MustTritsToTrytes(tree[len(tree)-curl.HashSize:])
+	branch := MerkleBranch(tree, depth, leafIndex)
+
+	return &Signature{
+		SignatureFragments: fragments,
+		Root:               root,
+		Branch:             MustTritsToTrytes(branch),
+		Index:              leafIndex,
+		SecurityLevel:      secLvl,
+	}, nil
+}
+
+// MerkleVerify recovers the leaf address implied by sig's signature fragments,
+// feeds it to MerkleRoot together with sig's authentication path, and checks
+// the recomputed root against sig.Root. spongeFunc must match the one the
+// tree was built and signed with.
+func MerkleVerify(bundleHash Hash, sig *Signature, depth uint64, spongeFunc ...func() sponge.SpongeFunction) (bool, error) {
+	newSponge := sponge.GetSpongeFunc(spongeFunc, sponge.NewKerl)
+
+	normalizedBundleHash := NormalizedBundleHash(bundleHash)
+
+	digestsTrits := make(Trits, len(sig.SignatureFragments)*curl.HashSize)
+	for i, fragTrytes := range sig.SignatureFragments {
+		digest, err := Digest(normalizedBundleHash[(i%3)*27:(i%3+1)*27], TrytesToTrits(fragTrytes), newSponge)
+		if err != nil {
+			return false, err
+		}
+		copy(digestsTrits[i*curl.HashSize:], digest)
+	}
+
+	addrTrits, err := Address(digestsTrits, newSponge)
+	if err != nil {
+		return false, err
+	}
+
+	root, err := MerkleRoot(addrTrits, TrytesToTrits(sig.Branch), depth, sig.Index, newSponge())
+	if err != nil {
+		return false, err
+	}
+
+	return MustTritsToTrytes(root) == sig.Root, nil
+}
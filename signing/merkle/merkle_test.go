@@ -0,0 +1,84 @@
+package merkle
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/iotaledger/giota/signing"
+	. "github.com/iotaledger/giota/trinary"
+)
+
+const tryteAlphabet = "9ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+func randomTrytes(rng *rand.Rand, n int) Trytes {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = tryteAlphabet[rng.Intn(len(tryteAlphabet))]
+	}
+	return Trytes(b)
+}
+
+// TestMerkleRoundTrip builds trees of a few depths, signs a random bundle
+// hash at a few leaf indices in each, and checks that MerkleVerify accepts
+// the result and recomputes the same root MerkleCreate returned.
+func TestMerkleRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	depths := []uint64{1, 2, 4}
+
+	for _, depth := range depths {
+		seed := randomTrytes(rng, 81)
+		root, tree, err := MerkleCreate(seed, depth, 0, SecurityLevelLow)
+		if err != nil {
+			t.Fatalf("depth %d: MerkleCreate failed: %s", depth, err)
+		}
+
+		leafCount := uint64(1) << depth
+		for leafIndex := uint64(0); leafIndex < leafCount; leafIndex++ {
+			bundleHash := Hash(randomTrytes(rng, 81))
+
+			sig, err := MerkleSign(bundleHash, seed, tree, depth, leafIndex, SecurityLevelLow)
+			if err != nil {
+				t.Fatalf("depth %d leaf %d: MerkleSign failed: %s", depth, leafIndex, err)
+			}
+			if sig.Root != root {
+				t.Fatalf("depth %d leaf %d: signature root %q does not match tree root %q", depth, leafIndex, sig.Root, root)
+			}
+
+			valid, err := MerkleVerify(bundleHash, sig, depth)
+			if err != nil {
+				t.Fatalf("depth %d leaf %d: MerkleVerify failed: %s", depth, leafIndex, err)
+			}
+			if !valid {
+				t.Fatalf("depth %d leaf %d: signature did not verify", depth, leafIndex)
+			}
+		}
+	}
+}
+
+// TestMerkleVerifyRejectsTamperedSignature checks that MerkleVerify rejects a
+// signature whose bundle hash was tampered with after signing.
+func TestMerkleVerifyRejectsTamperedSignature(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	seed := randomTrytes(rng, 81)
+	depth := uint64(3)
+
+	_, tree, err := MerkleCreate(seed, depth, 0, SecurityLevelLow)
+	if err != nil {
+		t.Fatalf("MerkleCreate failed: %s", err)
+	}
+
+	bundleHash := Hash(randomTrytes(rng, 81))
+	sig, err := MerkleSign(bundleHash, seed, tree, depth, 0, SecurityLevelLow)
+	if err != nil {
+		t.Fatalf("MerkleSign failed: %s", err)
+	}
+
+	tamperedHash := Hash(randomTrytes(rng, 81))
+	valid, err := MerkleVerify(tamperedHash, sig, depth)
+	if err != nil {
+		t.Fatalf("MerkleVerify failed: %s", err)
+	}
+	if valid {
+		t.Fatal("MerkleVerify accepted a signature for a different bundle hash than it was signed with")
+	}
+}
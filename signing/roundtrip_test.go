@@ -0,0 +1,71 @@
+package signing_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/iotaledger/giota/address"
+	. "github.com/iotaledger/giota/signing"
+	. "github.com/iotaledger/giota/trinary"
+)
+
+const tryteAlphabet = "9ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+func randomTrytes(rng *rand.Rand, n int) Trytes {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = tryteAlphabet[rng.Intn(len(tryteAlphabet))]
+	}
+	return Trytes(b)
+}
+
+// TestValidateSignaturesRoundTrip exercises GenerateAddress -> SignatureFragment
+// -> ValidateSignatures over many random seeds, indices, security levels and
+// bundle hashes. It guards against the NormalizedBundleHash indexing bug and the
+// ValidateSignatures nil-slice panic fixed in chunk1-4, both of which a single
+// hardcoded fixture could easily miss.
+func TestValidateSignaturesRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	secLvls := []SecurityLevel{SecurityLevelLow, SecurityLevelMedium, SecurityLevelHigh}
+
+	for trial := 0; trial < 100; trial++ {
+		seed := randomTrytes(rng, 81)
+		index := uint64(rng.Intn(100))
+		secLvl := secLvls[rng.Intn(len(secLvls))]
+		bundleHash := Hash(randomTrytes(rng, 81))
+
+		addr, err := address.GenerateAddress(seed, index, secLvl)
+		if err != nil {
+			t.Fatalf("trial %d: GenerateAddress failed: %s", trial, err)
+		}
+
+		subseed, err := Subseed(seed, index)
+		if err != nil {
+			t.Fatalf("trial %d: Subseed failed: %s", trial, err)
+		}
+		key, err := Key(subseed, secLvl)
+		if err != nil {
+			t.Fatalf("trial %d: Key failed: %s", trial, err)
+		}
+
+		normalizedBundleHash := NormalizedBundleHash(bundleHash)
+
+		fragments := make([]Trytes, int(secLvl))
+		for i := 0; i < int(secLvl); i++ {
+			keyFragment := key[i*KeyFragmentLength : (i+1)*KeyFragmentLength]
+			sigFragment, err := SignatureFragment(normalizedBundleHash[(i%3)*27:(i%3+1)*27], keyFragment)
+			if err != nil {
+				t.Fatalf("trial %d: SignatureFragment failed: %s", trial, err)
+			}
+			fragments[i] = MustTritsToTrytes(sigFragment)
+		}
+
+		valid, err := ValidateSignatures(addr, fragments, bundleHash)
+		if err != nil {
+			t.Fatalf("trial %d: ValidateSignatures failed: %s", trial, err)
+		}
+		if !valid {
+			t.Fatalf("trial %d: signature for seed %q index %d secLvl %d did not validate", trial, seed, index, secLvl)
+		}
+	}
+}
@@ -3,10 +3,13 @@ package signing
 import (
 	"errors"
 	"github.com/iotaledger/giota/curl"
-	"github.com/iotaledger/giota/kerl"
+	"github.com/iotaledger/giota/signing/sponge"
 	. "github.com/iotaledger/giota/trinary"
 	"math"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 const (
@@ -26,6 +29,35 @@ var (
 	EmptyAddress = strings.Repeat("9", 81)
 )
 
+// parallelism bounds how many goroutines Digests and ValidateSignatures use to
+// hash independent key fragments/segments concurrently. Defaults to
+// runtime.GOMAXPROCS(0); override with SetParallelism. It is an atomic.Int32,
+// not a bare int, since SetParallelism can race with concurrent Digests/
+// ValidateSignatures calls reading it via workerCount.
+var parallelism atomic.Int32
+
+func init() {
+	parallelism.Store(int32(runtime.GOMAXPROCS(0)))
+}
+
+// SetParallelism overrides the number of goroutines Digests and
+// ValidateSignatures use to fan out independent fragment/segment hashing.
+// Values below 1 are treated as 1 (serial).
+func SetParallelism(n int) {
+	parallelism.Store(int32(n))
+}
+
+func workerCount(n int) int {
+	w := int(parallelism.Load())
+	if w > n {
+		w = n
+	}
+	if w < 1 {
+		w = 1
+	}
+	return w
+}
+
 type SecurityLevel int
 
 const (
@@ -35,7 +67,7 @@ const (
 )
 
 // Subseed takes a seed and an index and returns the given subseed.
-func Subseed(seed Trytes, index uint64) (Trits, error) {
+func Subseed(seed Trytes, index uint64, spongeFunc ...func() sponge.SpongeFunction) (Trits, error) {
 	if err := ValidTrytes(seed); err != nil {
 		return nil, err
 	} else if len(seed) != TritHashLength/Radix {
@@ -48,7 +80,8 @@ func Subseed(seed Trytes, index uint64) (Trits, error) {
 		IncTrits(incrementedSeed)
 	}
 
-	k := kerl.NewKerl()
+	newSponge := sponge.GetSpongeFunc(spongeFunc, sponge.NewKerl)
+	k := newSponge()
 	err := k.Absorb(incrementedSeed)
 	if err != nil {
 		return nil, err
@@ -61,8 +94,9 @@ func Subseed(seed Trytes, index uint64) (Trits, error) {
 }
 
 // Key computes a new private key from the given subseed using the given security level.
-func Key(subseed Trits, securityLevel SecurityLevel) (Trits, error) {
-	k := kerl.NewKerl()
+func Key(subseed Trits, securityLevel SecurityLevel, spongeFunc ...func() sponge.SpongeFunction) (Trits, error) {
+	newSponge := sponge.GetSpongeFunc(spongeFunc, sponge.NewKerl)
+	k := newSponge()
 	if err := k.Absorb(subseed); err != nil {
 		return nil, err
 	}
@@ -83,56 +117,86 @@ func Key(subseed Trits, securityLevel SecurityLevel) (Trits, error) {
 }
 
 // Digests hashes each segment of each key fragment 26 times and returns them.
-func Digests(key Trits) (Trits, error) {
-	var err error
+// Each of the N independent 6561-trit key fragments is hashed on its own
+// goroutine, up to a parallelism (see SetParallelism) worker pool, since a
+// fragment's own sponge instances are never shared with another fragment's.
+func Digests(key Trits, spongeFunc ...func() sponge.SpongeFunction) (Trits, error) {
+	newSponge := sponge.GetSpongeFunc(spongeFunc, sponge.NewKerl)
 	fragments := int(math.Floor(float64(len(key)) / 6561))
 	digests := make(Trits, fragments*243)
-	buf := make(Trits, curl.HashSize)
 
-	// iterate through each key fragment
-	for i := 0; i < fragments; i++ {
-		keyFragment := key[i*6561 : (i+1)*6561]
+	sem := make(chan struct{}, workerCount(fragments))
+	errCh := make(chan error, fragments)
+	var wg sync.WaitGroup
 
-		// each fragment consists of 27 segments
-		for j := 0; j < 27; j++ {
-			copy(buf, keyFragment[j*243:(j+1)*243])
-
-			// hash each segment 26 times
-			for k := 0; k < 26; k++ {
-				k := kerl.NewKerl()
-				k.Absorb(buf)
-				buf, err = k.Squeeze(curl.HashSize)
-				if err != nil {
-					return nil, err
+	for i := 0; i < fragments; i++ {
+		i := i
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			keyFragment := key[i*6561 : (i+1)*6561]
+			buf := make(Trits, curl.HashSize)
+
+			// each fragment consists of 27 segments
+			for j := 0; j < 27; j++ {
+				copy(buf, keyFragment[j*243:(j+1)*243])
+
+				// hash each segment 26 times
+				for k := 0; k < 26; k++ {
+					var err error
+					kk := newSponge()
+					kk.Absorb(buf)
+					buf, err = kk.Squeeze(curl.HashSize)
+					if err != nil {
+						select {
+						case errCh <- err:
+						default:
+						}
+						return
+					}
 				}
+
+				copy(keyFragment[j*243:(j+1)*243], buf)
 			}
 
-			for k := 0; k < 243; k++ {
-				keyFragment[j*243+k] = buf[k]
+			// hash the key fragment (which now consists of hashed segments)
+			k := newSponge()
+			if err := k.Absorb(keyFragment); err != nil {
+				select {
+				case errCh <- err:
+				default:
+				}
+				return
 			}
-		}
 
-		// hash the key fragment (which now consists of hashed segments)
-		k := kerl.NewKerl()
-		if err := k.Absorb(keyFragment); err != nil {
-			return nil, err
-		}
+			fragDigest, err := k.Squeeze(curl.HashSize)
+			if err != nil {
+				select {
+				case errCh <- err:
+				default:
+				}
+				return
+			}
+			copy(digests[i*243:(i+1)*243], fragDigest)
+		}()
+	}
 
-		buf, err := k.Squeeze(curl.HashSize)
-		if err != nil {
-			return nil, err
-		}
-		for j := 0; j < 243; j++ {
-			digests[i*243+j] = buf[j]
-		}
+	wg.Wait()
+	close(errCh)
+	if err, has := <-errCh; has {
+		return nil, err
 	}
 
 	return digests, nil
 }
 
 // Address generates the address trits from the given digests.
-func Address(digests Trits) (Trits, error) {
-	k := kerl.NewKerl()
+func Address(digests Trits, spongeFunc ...func() sponge.SpongeFunction) (Trits, error) {
+	newSponge := sponge.GetSpongeFunc(spongeFunc, sponge.NewKerl)
+	k := newSponge()
 	if err := k.Absorb(digests); err != nil {
 		return nil, err
 	}
@@ -140,11 +204,12 @@ func Address(digests Trits) (Trits, error) {
 }
 
 // SignatureFragment returns signed fragments using the given key fragment.
-func SignatureFragment(normalizedBundleFragments Trits, keyFragment Trits) (Trits, error) {
+func SignatureFragment(normalizedBundleFragments Trits, keyFragment Trits, spongeFunc ...func() sponge.SpongeFunction) (Trits, error) {
 	sigFrag := make(Trits, len(keyFragment))
 	copy(sigFrag, keyFragment)
 
-	k := kerl.NewKerl()
+	newSponge := sponge.GetSpongeFunc(spongeFunc, sponge.NewKerl)
+	k := newSponge()
 
 	for i := 0; i < 27; i++ {
 		hash := sigFrag[i*243 : (i+1)*243]
@@ -171,8 +236,10 @@ func SignatureFragment(normalizedBundleFragments Trits, keyFragment Trits) (Trit
 }
 
 // ValidateSignatures validates the given fragments.
+// Each fragment's Digest is independent of the others, so they are computed
+// on a worker pool sized by workerCount, same as Digests.
 func ValidateSignatures(expectedAddress Hash, fragments []Trytes, bundleHash Hash) (bool, error) {
-	normalizedBundleHashFragments := []Trits{}
+	normalizedBundleHashFragments := make([]Trits, 3)
 	normalizeBundleHash := NormalizedBundleHash(bundleHash)
 
 	for i := 0; i < 3; i++ {
@@ -180,14 +247,35 @@ func ValidateSignatures(expectedAddress Hash, fragments []Trytes, bundleHash Has
 	}
 
 	digests := make(Trits, len(fragments)*243)
+
+	sem := make(chan struct{}, workerCount(len(fragments)))
+	errCh := make(chan error, len(fragments))
+	var wg sync.WaitGroup
+
 	for i := 0; i < len(fragments); i++ {
-		digest, err := Digest(normalizedBundleHashFragments[i%3], TrytesToTrits(fragments[i]))
-		if err != nil {
-			return false, err
-		}
-		for j := 0; j < 243; j++ {
-			digests[i*243+j] = digest[j]
-		}
+		i := i
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			digest, err := Digest(normalizedBundleHashFragments[i%3], TrytesToTrits(fragments[i]))
+			if err != nil {
+				select {
+				case errCh <- err:
+				default:
+				}
+				return
+			}
+			copy(digests[i*243:(i+1)*243], digest)
+		}()
+	}
+
+	wg.Wait()
+	close(errCh)
+	if err, has := <-errCh; has {
+		return false, err
 	}
 
 	addressTrits, err := Address(digests)
@@ -198,15 +286,16 @@ func ValidateSignatures(expectedAddress Hash, fragments []Trytes, bundleHash Has
 }
 
 // Digest computes the digest derived from the signature fragment and normalized bundle hash.
-func Digest(normalizedBundleHashFragment Trits, signatureFragment Trits) (Trits, error) {
-	k := kerl.NewKerl()
+func Digest(normalizedBundleHashFragment Trits, signatureFragment Trits, spongeFunc ...func() sponge.SpongeFunction) (Trits, error) {
+	newSponge := sponge.GetSpongeFunc(spongeFunc, sponge.NewKerl)
+	k := newSponge()
 	buf := make(Trits, curl.HashSize)
 
 	for i := 0; i < 27; i++ {
 		copy(buf, signatureFragment[i*243:(i+1)*243])
 
 		for j := normalizedBundleHashFragment[i] + 13; j > 0; j-- {
-			kk := kerl.NewKerl()
+			kk := newSponge()
 			err := kk.Absorb(buf)
 			if err != nil {
 				return nil, err
@@ -225,13 +314,33 @@ func Digest(normalizedBundleHashFragment Trits, signatureFragment Trits) (Trits,
 	return k.Squeeze(curl.HashSize)
 }
 
+// tryteAlphabet is the standard IOTA tryte alphabet. A tryte's index into it
+// is its unsigned value; values above 13 wrap around to their negative
+// counterpart ("N"..."Z" are -13...-1), matching TrytesToTrits's per-tryte encoding.
+const tryteAlphabet = "9ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// tryteValue maps a tryte character directly to its signed trit-sum value, so
+// NormalizedBundleHash can decode a bundle hash without allocating a string
+// and a Trits slice per tryte.
+var tryteValue = func() [256]int8 {
+	var t [256]int8
+	for i := 0; i < len(tryteAlphabet); i++ {
+		v := i
+		if v > 13 {
+			v -= 27
+		}
+		t[tryteAlphabet[i]] = int8(v)
+	}
+	return t
+}()
+
 // NormalizedBundleHash normalizes the given bundle hash, with resulting digits summing to zero.
 func NormalizedBundleHash(bundleHash Hash) Trits {
 	normalizedBundle := make([]int8, curl.HashSize)
 	for i := 0; i < 3; i++ {
 		sum := 0
 		for j := 0; j < 27; j++ {
-			normalizedBundle[i*27+j] = int8(TritsToInt(TrytesToTrits(string(bundleHash[i*27*j]))))
+			normalizedBundle[i*27+j] = tryteValue[bundleHash[i*27+j]]
 			sum += int(normalizedBundle[i*27+j])
 		}
 
@@ -0,0 +1,62 @@
+package signing
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	. "github.com/iotaledger/giota/trinary"
+)
+
+const digestsBenchSeed Trytes = "NOPQRSTUVWXYZ9ABCDEFGHIJKLMNOPQRSTUVWXYZ9ABCDEFGHIJKLMNOPQRSTUVWXYZ9ABCDEFGHIJ"
+
+var digestsBenchSecLvls = []SecurityLevel{SecurityLevelLow, SecurityLevelMedium, SecurityLevelHigh}
+var digestsBenchCounts = []int{1, 16, 256}
+
+// BenchmarkDigests compares Digests run serially (SetParallelism(1)) against the
+// GOMAXPROCS-based default, across every security level and call count named in
+// the chunk1-3 request. Digests mutates its key argument in place, so each
+// iteration hashes a fresh copy of the precomputed key.
+func BenchmarkDigests(b *testing.B) {
+	defaultParallelism := runtime.GOMAXPROCS(0)
+	defer SetParallelism(defaultParallelism)
+
+	for _, secLvl := range digestsBenchSecLvls {
+		subseed, err := Subseed(digestsBenchSeed, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		key, err := Key(subseed, secLvl)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for _, count := range digestsBenchCounts {
+			b.Run(fmt.Sprintf("serial/secLvl=%d/count=%d", secLvl, count), func(b *testing.B) {
+				SetParallelism(1)
+				for i := 0; i < b.N; i++ {
+					for j := 0; j < count; j++ {
+						keyCopy := make(Trits, len(key))
+						copy(keyCopy, key)
+						if _, err := Digests(keyCopy); err != nil {
+							b.Fatal(err)
+						}
+					}
+				}
+			})
+
+			b.Run(fmt.Sprintf("parallel/secLvl=%d/count=%d", secLvl, count), func(b *testing.B) {
+				SetParallelism(defaultParallelism)
+				for i := 0; i < b.N; i++ {
+					for j := 0; j < count; j++ {
+						keyCopy := make(Trits, len(key))
+						copy(keyCopy, key)
+						if _, err := Digests(keyCopy); err != nil {
+							b.Fatal(err)
+						}
+					}
+				}
+			})
+		}
+	}
+}
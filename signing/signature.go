@@ -0,0 +1,53 @@
+package signing
+
+import (
+	"errors"
+
+	. "github.com/iotaledger/giota/trinary"
+)
+
+// FragmentLength is the length, in trytes, of a single signature fragment.
+const FragmentLength = KeyFragmentLength / 3
+
+// ErrInvalidSignatureLength gets returned when trytes passed to ParseSignature
+// aren't a non-zero multiple of FragmentLength.
+var ErrInvalidSignatureLength = errors.New("signature trytes length must be a non-zero multiple of the fragment length")
+
+// Signature is a typed Winternitz signature: one fragment per security level,
+// together with the address and bundle hash it was produced for, so callers
+// validating a signature don't need to thread raw fragments and hashes separately.
+type Signature struct {
+	Fragments  []Trytes
+	Address    Hash
+	BundleHash Hash
+}
+
+// Validate reports whether s.Fragments is a valid signature of s.BundleHash under s.Address.
+func (s *Signature) Validate() (bool, error) {
+	return ValidateSignatures(s.Address, s.Fragments, s.BundleHash)
+}
+
+// Trytes concatenates s's fragments into their wire representation.
+func (s *Signature) Trytes() Trytes {
+	var trytes Trytes
+	for _, fragment := range s.Fragments {
+		trytes += fragment
+	}
+	return trytes
+}
+
+// ParseSignature splits trytes into fragments of FragmentLength, inferring the
+// security level from its length. Address and BundleHash are left empty, since
+// neither is recoverable from the signature trytes alone.
+func ParseSignature(trytes Trytes) (*Signature, error) {
+	if len(trytes) == 0 || len(trytes)%FragmentLength != 0 {
+		return nil, ErrInvalidSignatureLength
+	}
+
+	fragments := make([]Trytes, len(trytes)/FragmentLength)
+	for i := range fragments {
+		fragments[i] = trytes[i*FragmentLength : (i+1)*FragmentLength]
+	}
+
+	return &Signature{Fragments: fragments}, nil
+}
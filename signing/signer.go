@@ -0,0 +1,102 @@
+package signing
+
+import (
+	"errors"
+
+	"github.com/iotaledger/giota/curl"
+	"github.com/iotaledger/giota/signing/sponge"
+	. "github.com/iotaledger/giota/trinary"
+)
+
+// ErrNoFragmentsLeft gets returned by NextFragment once a Signer has already
+// produced secLvl fragments.
+var ErrNoFragmentsLeft = errors.New("signer has already produced secLvl fragments")
+
+// Signer incrementally derives and signs one key fragment at a time, instead
+// of holding the full secLvl*6561-trit key in memory the way Key/Digests do.
+// Each NextFragment call re-derives just the fragment it needs from the
+// subseed-absorbed sponge state and discards it once signed, so peak memory
+// stays O(KeyFragmentLength) regardless of secLvl. This also gives an
+// HSM-style wrapper a chance to intercept each fragment individually.
+type Signer struct {
+	subseed   Trits
+	secLvl    SecurityLevel
+	fragment  int
+	newSponge func() sponge.SpongeFunction
+}
+
+// NewSigner derives the subseed for seed at index once and returns a Signer
+// able to produce up to int(secLvl) signed fragments, one per NextFragment call.
+func NewSigner(seed Trytes, index uint64, secLvl SecurityLevel, spongeFunc ...func() sponge.SpongeFunction) (*Signer, error) {
+	newSponge := sponge.GetSpongeFunc(spongeFunc, sponge.NewKerl)
+
+	subseed, err := Subseed(seed, index, newSponge)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{
+		subseed:   subseed,
+		secLvl:    secLvl,
+		newSponge: newSponge,
+	}, nil
+}
+
+// NextFragment derives the next 6561-trit (2187-tryte) key fragment, signs it against
+// normalizedBundleFragment and discards the key fragment before returning.
+// It must be called at most int(secLvl) times per Signer.
+func (s *Signer) NextFragment(normalizedBundleFragment Trits) (Trits, error) {
+	if s.fragment >= int(s.secLvl) {
+		return nil, ErrNoFragmentsLeft
+	}
+
+	keyFragment, err := s.deriveFragment(s.fragment)
+	if err != nil {
+		return nil, err
+	}
+
+	sigFragment, err := SignatureFragment(normalizedBundleFragment, keyFragment, s.newSponge)
+	if err != nil {
+		return nil, err
+	}
+
+	zeroTrits(keyFragment)
+	s.fragment++
+	return sigFragment, nil
+}
+
+// deriveFragment re-absorbs the subseed and squeezes through the fragments
+// preceding index, discarding them, before squeezing out index's own 27 segments.
+func (s *Signer) deriveFragment(index int) (Trits, error) {
+	k := s.newSponge()
+	if err := k.Absorb(s.subseed); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < index*27; i++ {
+		if _, err := k.Squeeze(curl.HashSize); err != nil {
+			return nil, err
+		}
+	}
+
+	fragment := make(Trits, KeyFragmentLength)
+	for j := 0; j < 27; j++ {
+		segment, err := k.Squeeze(curl.HashSize)
+		if err != nil {
+			return nil, err
+		}
+		copy(fragment[j*curl.HashSize:], segment)
+	}
+	return fragment, nil
+}
+
+// Close zeroes the Signer's subseed so it doesn't linger in memory once signing completes.
+func (s *Signer) Close() {
+	zeroTrits(s.subseed)
+}
+
+func zeroTrits(t Trits) {
+	for i := range t {
+		t[i] = 0
+	}
+}
@@ -0,0 +1,53 @@
+// Package sponge defines the sponge abstraction used throughout the signing
+// package, so that its primitives aren't hardwired to Kerl. Merkle/MAM flows
+// need Curl-P-27, and test harnesses sometimes want to swap in a mock; both
+// only need to satisfy SpongeFunction.
+package sponge
+
+import (
+	"github.com/iotaledger/giota/curl"
+	"github.com/iotaledger/giota/kerl"
+	. "github.com/iotaledger/giota/trinary"
+)
+
+// SpongeFunction is the common interface satisfied by every sponge construction
+// usable by the signing package.
+type SpongeFunction interface {
+	Absorb(Trits) error
+	Squeeze(int) (Trits, error)
+	Reset()
+}
+
+// NewKerl constructs a new Kerl sponge. This is the default used throughout
+// the signing package.
+func NewKerl() SpongeFunction {
+	return kerl.NewKerl()
+}
+
+// NewCurlP27 constructs a new 27-round Curl sponge.
+func NewCurlP27() SpongeFunction {
+	c, err := curl.NewCurl(curl.CurlP27)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// NewCurlP81 constructs a new 81-round Curl sponge.
+func NewCurlP81() SpongeFunction {
+	c, err := curl.NewCurl(curl.CurlP81)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// GetSpongeFunc returns the first of the given sponge constructors, or
+// defaultFunc if none was supplied. Signing functions use this to pick
+// between a caller-supplied sponge and their own default.
+func GetSpongeFunc(funcs []func() SpongeFunction, defaultFunc func() SpongeFunction) func() SpongeFunction {
+	if len(funcs) > 0 && funcs[0] != nil {
+		return funcs[0]
+	}
+	return defaultFunc
+}